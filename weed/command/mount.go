@@ -0,0 +1,67 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+)
+
+type MountOptions struct {
+	filer              *string
+	filerGrpcPort      *int
+	filerMountRootPath *string
+	dir                *string
+	dirListingLimit    *int
+	collection         *string
+	replication        *string
+	ttlSec             *int
+	chunkSizeLimitMB   *int
+	dataCenter         *string
+
+	cacheDir       *string
+	cacheSizeMB    *int64
+	memCacheSizeMB *int64
+
+	readAheadChunks      *int
+	readAheadConcurrency *int
+}
+
+var (
+	mountOptions MountOptions
+)
+
+func init() {
+	cmdMount.Run = runMount
+
+	mountOptions.filer = cmdMount.Flag.String("filer", "localhost:8888", "weed filer location")
+	mountOptions.filerGrpcPort = cmdMount.Flag.Int("filer.grpc.port", 0, "filer grpc server listen port, default to filer port + 10000")
+	mountOptions.filerMountRootPath = cmdMount.Flag.String("filer.path", "/", "mount this remote path from filer server")
+	mountOptions.dir = cmdMount.Flag.String("dir", ".", "mount weed filer to this directory")
+	mountOptions.dirListingLimit = cmdMount.Flag.Int("dirListLimit", 100000, "limit of directory listing")
+	mountOptions.collection = cmdMount.Flag.String("collection", "", "collection to create the files")
+	mountOptions.replication = cmdMount.Flag.String("replication", "", "replication to create the files")
+	mountOptions.ttlSec = cmdMount.Flag.Int("ttl", 0, "file ttl in seconds")
+	mountOptions.chunkSizeLimitMB = cmdMount.Flag.Int("chunkSizeLimitMB", 2, "local write buffer size, also chunk large files")
+	mountOptions.dataCenter = cmdMount.Flag.String("dataCenter", "", "prefer to write to the data center")
+
+	mountOptions.cacheDir = cmdMount.Flag.String("cacheDir", filepath.Join(os.TempDir(), "seaweedfs_chunk_cache"), "local directory to store on-disk chunk cache")
+	mountOptions.cacheSizeMB = cmdMount.Flag.Int64("cacheSizeMB", 1000, "on-disk chunk cache capacity in MB, 0 to disable")
+	mountOptions.memCacheSizeMB = cmdMount.Flag.Int64("memCacheMB", 64, "in-memory chunk cache capacity in MB, 0 to disable")
+
+	mountOptions.readAheadChunks = cmdMount.Flag.Int("readAheadChunks", 0, "number of following chunks to prefetch on sequential reads, 0 to disable")
+	mountOptions.readAheadConcurrency = cmdMount.Flag.Int("readAheadConcurrency", 4, "max number of concurrent readahead fetches")
+}
+
+var cmdMount = &Command{
+	UsageLine: "mount -filer=localhost:8888 -dir=/some/dir",
+	Short:     "mount weed filer to a directory as file system",
+	Long: `mount weed filer to a directory as file system.
+
+  Pre-requisites:
+  1) have a seaweed filer running
+  2) have the fuse wrapper binary "bazil.org/fuse/cmd/fusermount" installed
+
+  This uses bazil.org/fuse, which enables mounting in non-root user.
+  Most of the mount features are implemented. Please report any issues.
+
+  `,
+}
@@ -5,6 +5,7 @@ package command
 import (
 	"fmt"
 	"runtime"
+	"time"
 
 	"bazil.org/fuse"
 	"bazil.org/fuse/fs"
@@ -59,9 +60,22 @@ func runMount(cmd *Command, args []string) bool {
 		return false
 	}
 
-	err = fs.Serve(c, filesys.NewSeaweedFileSystem(
-		filerGrpcAddress, *mountOptions.filerMountRootPath, *mountOptions.collection, *mountOptions.replication, int32(*mountOptions.ttlSec),
-		*mountOptions.chunkSizeLimitMB, *mountOptions.dataCenter))
+	err = fs.Serve(c, filesys.NewSeaweedFileSystem(&filesys.Option{
+		FilerGrpcAddress:     filerGrpcAddress,
+		FilerMountRootPath:   *mountOptions.filerMountRootPath,
+		Collection:           *mountOptions.collection,
+		Replication:          *mountOptions.replication,
+		TtlSec:               int32(*mountOptions.ttlSec),
+		ChunkSizeLimit:       int64(*mountOptions.chunkSizeLimitMB) * 1024 * 1024,
+		DataCenter:           *mountOptions.dataCenter,
+		DirListingLimit:      *mountOptions.dirListingLimit,
+		EntryCacheTtl:        3 * time.Second,
+		ChunkCacheSizeMB:     *mountOptions.memCacheSizeMB,
+		CacheDir:             *mountOptions.cacheDir,
+		CacheSizeMB:          *mountOptions.cacheSizeMB,
+		ReadAheadChunks:      *mountOptions.readAheadChunks,
+		ReadAheadConcurrency: *mountOptions.readAheadConcurrency,
+	}))
 	if err != nil {
 		fuse.Unmount(*mountOptions.dir)
 	}
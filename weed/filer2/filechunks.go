@@ -0,0 +1,91 @@
+package filer2
+
+import (
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+)
+
+// ChunkView describes the byte range within a single chunk's own content that
+// overlaps a requested read range.
+type ChunkView struct {
+	FileId      string
+	CipherKey   []byte
+	Offset      int64 // offset into the chunk's own content
+	Size        int64
+	LogicOffset int64 // offset into the file as a whole
+	ChunkIndex  int   // this chunk's position in the entry's chunk list
+}
+
+// ViewFromChunks resolves which chunks, and which byte ranges within them,
+// overlap [offset, offset+size) in file order. Chunks entirely outside the
+// requested range are skipped.
+func ViewFromChunks(chunks []*filer_pb.FileChunk, offset int64, size int) (views []*ChunkView) {
+	stop := offset + int64(size)
+
+	var pos int64
+	for i, chunk := range chunks {
+		chunkStart := pos
+		chunkStop := pos + chunk.Size
+		pos = chunkStop
+
+		if chunkStop <= offset || chunkStart >= stop {
+			continue
+		}
+
+		viewStart := chunkStart
+		if offset > viewStart {
+			viewStart = offset
+		}
+		viewStop := chunkStop
+		if stop < viewStop {
+			viewStop = stop
+		}
+
+		views = append(views, &ChunkView{
+			FileId:      chunk.GetFileIdString(),
+			CipherKey:   chunk.CipherKey,
+			Offset:      viewStart - chunkStart,
+			Size:        viewStop - viewStart,
+			LogicOffset: viewStart,
+			ChunkIndex:  i,
+		})
+	}
+	return
+}
+
+// ChunkRangeIterator walks a chunk list one chunk at a time, pairing each
+// ChunkView with its position in the file. Splitting this out of the
+// formerly monolithic streaming code lets callers (e.g. readahead) fetch
+// individual chunks concurrently instead of only being able to stream a
+// whole range through a single writer.
+type ChunkRangeIterator struct {
+	views []*ChunkView
+	pos   int
+}
+
+// NewChunkRangeIterator resolves the chunks overlapping [offset, offset+size)
+// and returns an iterator over them, in file order.
+func NewChunkRangeIterator(chunks []*filer_pb.FileChunk, offset int64, size int) *ChunkRangeIterator {
+	return &ChunkRangeIterator{views: ViewFromChunks(chunks, offset, size)}
+}
+
+// Next returns the next ChunkView in file order, or found=false once the
+// range is exhausted. ChunkView.ChunkIndex identifies its position in the
+// entry's full chunk list, so callers can drive readahead off of it.
+func (it *ChunkRangeIterator) Next() (chunkView *ChunkView, found bool) {
+	if it.pos >= len(it.views) {
+		return nil, false
+	}
+	chunkView = it.views[it.pos]
+	it.pos++
+	return chunkView, true
+}
+
+// FileIds returns every chunk's fileId for the whole file, in order, for use
+// as a readahead lookahead window.
+func FileIds(chunks []*filer_pb.FileChunk) (fileIds []string) {
+	fileIds = make([]string, len(chunks))
+	for i, chunk := range chunks {
+		fileIds[i] = chunk.GetFileIdString()
+	}
+	return
+}
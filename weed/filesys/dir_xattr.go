@@ -0,0 +1,23 @@
+package filesys
+
+import (
+	"context"
+
+	"bazil.org/fuse"
+	"bazil.org/fuse/fs"
+)
+
+// debugCacheXattrName is a synthetic xattr (not backed by filer metadata)
+// that surfaces the chunk cache's tier sizes for debugging, e.g.
+// `getfattr -n user.seaweedfs.cache <mount>`.
+const debugCacheXattrName = "user.seaweedfs.cache"
+
+var _ fs.NodeGetxattrer = (*Dir)(nil)
+
+func (dir *Dir) Getxattr(ctx context.Context, req *fuse.GetxattrRequest, resp *fuse.GetxattrResponse) error {
+	if req.Name != debugCacheXattrName {
+		return fuse.ErrNoXattr
+	}
+	resp.Xattr = []byte(dir.wfs.chunkCacheStats())
+	return nil
+}
@@ -0,0 +1,106 @@
+package filesys
+
+import (
+	"context"
+	"sync"
+
+	"bazil.org/fuse"
+	"bazil.org/fuse/fs"
+	"github.com/chrislusf/seaweedfs/weed/filer2"
+)
+
+// FileHandle represents an open file. A handle is reused across repeated
+// opens of the same path (see WFS.AcquireHandle) and released on the last
+// close (see WFS.ReleaseHandle).
+type FileHandle struct {
+	handle uint64
+
+	f   *File
+	uid uint32
+	gid uint32
+
+	// readAheadOnce lazily builds ra for this handle's entry on first Read,
+	// since the chunk list (and thus the readahead lookahead window) is not
+	// known until then.
+	readAheadOnce sync.Once
+	ra            *readAhead
+}
+
+func newFileHandle(file *File, uid, gid uint32) *FileHandle {
+	return &FileHandle{f: file, uid: uid, gid: gid}
+}
+
+func (fh *FileHandle) InitializeToFile(file *File, uid, gid uint32) {
+	fh.f = file
+	fh.uid = uid
+	fh.gid = gid
+}
+
+var _ fs.HandleReader = (*FileHandle)(nil)
+
+// Read serves a read request by resolving which chunks overlap the requested
+// range and fetching each one through the WFS chunk cache, so repeat reads of
+// the same chunk (common with retries, overlapping ranges, and re-opens)
+// skip the volume server entirely. On sequential access it also triggers
+// background readahead of the chunks likely to be read next.
+func (fh *FileHandle) Read(ctx context.Context, req *fuse.ReadRequest, resp *fuse.ReadResponse) error {
+
+	entry := fh.f.getEntry()
+	if entry == nil || len(entry.Chunks) == 0 {
+		return nil
+	}
+
+	fh.readAheadOnce.Do(func() {
+		fh.ra = newReadAhead(fh.f.wfs, filer2.FileIds(entry.Chunks), fh.f.wfs.fetchRawChunk)
+	})
+
+	buf := make([]byte, 0, req.Size)
+
+	it := filer2.NewChunkRangeIterator(entry.Chunks, req.Offset, req.Size)
+	for {
+		chunkView, found := it.Next()
+		if !found {
+			break
+		}
+
+		data, err := fh.fetchChunk(ctx, chunkView.FileId, chunkView.CipherKey)
+		if err != nil {
+			return err
+		}
+
+		start := chunkView.Offset
+		end := start + chunkView.Size
+		if start > int64(len(data)) {
+			start = int64(len(data))
+		}
+		if end > int64(len(data)) {
+			end = int64(len(data))
+		}
+
+		buf = append(buf, data[start:end]...)
+
+		fh.ra.onRead(chunkView.ChunkIndex)
+	}
+
+	resp.Data = buf
+
+	return nil
+}
+
+// fetchChunk returns a chunk's decrypted content, preferring whatever
+// readahead already landed (the chunk cache, or its ring-buffer fallback
+// when no cache is configured) over fetching it again.
+func (fh *FileHandle) fetchChunk(ctx context.Context, fileId string, cipherKey []byte) ([]byte, error) {
+	if raw, found := fh.ra.getCached(fileId); found {
+		return decryptChunk(raw, cipherKey)
+	}
+	return fh.f.wfs.readChunkCached(ctx, fileId, cipherKey)
+}
+
+// releaseReadAhead cancels any in-flight or scheduled prefetches for this
+// handle so they don't outlive it. Called by WFS.ReleaseHandle.
+func (fh *FileHandle) releaseReadAhead() {
+	if fh.ra != nil {
+		fh.ra.stop()
+	}
+}
@@ -0,0 +1,183 @@
+package filesys
+
+import (
+	"context"
+	"sync"
+
+	"github.com/chrislusf/seaweedfs/weed/glog"
+)
+
+// chunkFetcher fetches a single chunk's content (ciphertext when encrypted)
+// by fileId, the same call FileHandle.Read makes on a cache miss. It must
+// return promptly once ctx is cancelled.
+type chunkFetcher func(ctx context.Context, fileId string) ([]byte, error)
+
+// readAhead tracks sequential access on a single FileHandle and schedules
+// background fetches of the next ReadAheadChunks chunks, landing results in
+// the WFS chunk cache so the foreground read path picks them up for free. If
+// the mount has no chunk cache configured, fetched bytes are instead kept in
+// a small per-handle ring buffer (see getCached) so prefetching isn't wasted
+// work. It is cancelled via stop() when the owning handle is released, which
+// aborts any in-flight fetches so they never outlive the file they were
+// started for.
+type readAhead struct {
+	wfs     *WFS
+	fetch   chunkFetcher
+	fileIds []string // ordered chunk fileIds for the file, index == chunk position
+
+	ring *chunkRingBuffer // non-nil only when wfs.chunkCache is disabled
+
+	sem chan struct{} // bounds concurrent prefetches to ReadAheadConcurrency
+	wg  sync.WaitGroup
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu         sync.Mutex
+	lastOffset int64 // guarded by mu
+}
+
+func newReadAhead(wfs *WFS, fileIds []string, fetch chunkFetcher) *readAhead {
+	concurrency := wfs.option.ReadAheadConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ra := &readAhead{
+		wfs:     wfs,
+		fetch:   fetch,
+		fileIds: fileIds,
+		sem:     make(chan struct{}, concurrency),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	if !wfs.chunkCache.Enabled() {
+		ra.ring = newChunkRingBuffer(wfs.option.ReadAheadChunks + 1)
+	}
+	return ra
+}
+
+// getCached returns previously fetched bytes for fileId, whether landed in
+// the real chunk cache or, when none is configured, this handle's ring
+// buffer. FileHandle.Read consults this before fetching so a chunk readahead
+// already pulled in isn't fetched a second time.
+func (ra *readAhead) getCached(fileId string) ([]byte, bool) {
+	if ra.ring != nil {
+		return ra.ring.get(fileId)
+	}
+	return ra.wfs.chunkCache.GetChunk(fileId)
+}
+
+// onRead is called by FileHandle.Read with the chunk index just served. If
+// the access pattern looks sequential (monotonically increasing by one chunk
+// at a time), it kicks off background fetches for the next N chunks.
+func (ra *readAhead) onRead(chunkIndex int) {
+	if ra.wfs.option.ReadAheadChunks <= 0 {
+		return
+	}
+
+	ra.mu.Lock()
+	offset := int64(chunkIndex)
+	sequential := offset == ra.lastOffset+1
+	ra.lastOffset = offset
+	ra.mu.Unlock()
+
+	if !sequential {
+		return
+	}
+
+	for i := 1; i <= ra.wfs.option.ReadAheadChunks; i++ {
+		next := chunkIndex + i
+		if next >= len(ra.fileIds) {
+			break
+		}
+		ra.prefetch(ra.fileIds[next])
+	}
+}
+
+func (ra *readAhead) prefetch(fileId string) {
+	if _, found := ra.getCached(fileId); found {
+		return
+	}
+
+	if ra.ctx.Err() != nil {
+		return
+	}
+
+	select {
+	case ra.sem <- struct{}{}:
+	default:
+		// all prefetch workers are busy; skip rather than block the reader
+		return
+	}
+
+	ra.wg.Add(1)
+	go func() {
+		defer ra.wg.Done()
+		defer func() { <-ra.sem }()
+
+		if ra.ctx.Err() != nil {
+			return
+		}
+
+		// fetch (WFS.fetchRawChunk) populates the chunk cache itself on a
+		// successful fetch, so the foreground reader picks this up for free.
+		// ra.ctx is cancelled by stop(), which aborts the underlying HTTP
+		// request rather than letting it run to completion unobserved.
+		data, err := ra.fetch(ra.ctx, fileId)
+		if err != nil {
+			glog.V(4).Infof("readahead fetch %s failed: %v", fileId, err)
+			return
+		}
+		if ra.ring != nil {
+			ra.ring.set(fileId, data)
+		}
+	}()
+}
+
+// stop cancels any in-flight or future prefetches for this handle, aborting
+// their underlying HTTP requests rather than letting them run to completion.
+func (ra *readAhead) stop() {
+	ra.cancel()
+}
+
+// chunkRingBuffer is a tiny fixed-capacity, FIFO-eviction cache used as a
+// readahead landing spot when the mount has no real chunk cache configured,
+// so prefetched bytes reach the foreground reader instead of being thrown
+// away the instant they're fetched.
+type chunkRingBuffer struct {
+	mu       sync.Mutex
+	capacity int
+	order    []string
+	data     map[string][]byte
+}
+
+func newChunkRingBuffer(capacity int) *chunkRingBuffer {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &chunkRingBuffer{capacity: capacity, data: make(map[string][]byte)}
+}
+
+func (r *chunkRingBuffer) get(fileId string) ([]byte, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data, found := r.data[fileId]
+	return data, found
+}
+
+func (r *chunkRingBuffer) set(fileId string, data []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, found := r.data[fileId]; found {
+		return
+	}
+	if len(r.order) >= r.capacity {
+		oldest := r.order[0]
+		r.order = r.order[1:]
+		delete(r.data, oldest)
+	}
+	r.order = append(r.order, fileId)
+	r.data[fileId] = data
+}
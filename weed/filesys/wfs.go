@@ -3,7 +3,10 @@ package filesys
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"math"
+	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -26,6 +29,13 @@ type Option struct {
 	DataCenter         string
 	DirListingLimit    int
 	EntryCacheTtl      time.Duration
+
+	ChunkCacheSizeMB int64
+	CacheDir         string
+	CacheSizeMB      int64
+
+	ReadAheadChunks      int
+	ReadAheadConcurrency int
 }
 
 var _ = fs.FS(&WFS{})
@@ -43,14 +53,30 @@ type WFS struct {
 	// cache grpc connections
 	grpcClients     map[string]*grpc.ClientConn
 	grpcClientsLock sync.Mutex
+
+	// cache the aggregated filer statistics used to answer Statfs
+	statsCache     filer_pb.StatisticsResponse
+	statsCacheTtl  time.Time
+	statsCacheLock sync.Mutex
+
+	// chunkCache holds fetched chunk content (ciphertext when encrypted) keyed
+	// by fileId, so repeat reads of the same chunk skip the volume server.
+	chunkCache *util.ChunkCache
 }
 
+// statsCacheDuration bounds how often Statfs hits the filer for fresh
+// cluster statistics. df and friends poll frequently, so a short-lived
+// cache keeps that traffic off the hot path without staling the numbers.
+// Matches the EntryCacheTtl default set by runMount.
+const statsCacheDuration = 3 * time.Second
+
 func NewSeaweedFileSystem(option *Option) *WFS {
 	return &WFS{
 		option:                    option,
 		listDirectoryEntriesCache: ccache.New(ccache.Configure().MaxSize(int64(option.DirListingLimit) + 200).ItemsToPrune(100)),
 		pathToHandleIndex:         make(map[string]int),
 		grpcClients:               make(map[string]*grpc.ClientConn),
+		chunkCache:                util.NewChunkCache(option.ChunkCacheSizeMB, option.CacheDir, option.CacheSizeMB),
 	}
 }
 
@@ -58,6 +84,13 @@ func (wfs *WFS) Root() (fs.Node, error) {
 	return &Dir{Path: wfs.option.FilerMountRootPath, wfs: wfs}, nil
 }
 
+// chunkCacheStats renders the chunk cache tier sizes, exposed via the
+// "user.seaweedfs.cache" debug xattr.
+func (wfs *WFS) chunkCacheStats() string {
+	memItems, memBytes, diskItems, diskBytes := wfs.chunkCache.Stats()
+	return fmt.Sprintf("mem: %d chunks, %d bytes; disk: %d chunks, %d bytes", memItems, memBytes, diskItems, diskBytes)
+}
+
 func (wfs *WFS) withFilerClient(fn func(filer_pb.SeaweedFilerClient) error) error {
 
 	wfs.grpcClientsLock.Lock()
@@ -128,6 +161,9 @@ func (wfs *WFS) ReleaseHandle(fullpath string, handleId fuse.HandleID) {
 	glog.V(4).Infof("%s releasing handle id %d current handles length %d", fullpath, handleId, len(wfs.handles))
 	delete(wfs.pathToHandleIndex, fullpath)
 	if int(handleId) < len(wfs.handles) {
+		if fileHandle := wfs.handles[int(handleId)]; fileHandle != nil {
+			fileHandle.releaseReadAhead()
+		}
 		wfs.handles[int(handleId)] = nil
 	}
 
@@ -139,9 +175,15 @@ func (wfs *WFS) Statfs(ctx context.Context, req *fuse.StatfsRequest, resp *fuse.
 
 	glog.V(4).Infof("reading fs stats: %+v", req)
 
-	totalDiskSize := uint64(0)
-	usedDiskSize := uint64(0)
-	actualFileCount := uint64(0)
+	stats, err := wfs.cachedStatistics(ctx)
+	if err != nil {
+		glog.V(0).Infof("filer Statistics: %v", err)
+		return fuse.EIO
+	}
+
+	totalDiskSize := stats.TotalSize
+	usedDiskSize := stats.UsedSize
+	actualFileCount := stats.FileCount
 
 	// Compute the total number of available blocks
 	resp.Blocks = totalDiskSize / blockSize
@@ -164,3 +206,131 @@ func (wfs *WFS) Statfs(ctx context.Context, req *fuse.StatfsRequest, resp *fuse.
 
 	return nil
 }
+
+// cachedStatistics returns the cluster-wide disk usage, scoped to the mount's
+// collection, refreshing from the filer only once every statsCacheDuration.
+func (wfs *WFS) cachedStatistics(ctx context.Context) (filer_pb.StatisticsResponse, error) {
+
+	wfs.statsCacheLock.Lock()
+	defer wfs.statsCacheLock.Unlock()
+
+	if time.Now().Before(wfs.statsCacheTtl) {
+		return wfs.statsCache, nil
+	}
+
+	var newStats filer_pb.StatisticsResponse
+	err := wfs.withFilerClient(func(client filer_pb.SeaweedFilerClient) error {
+		resp, err := client.Statistics(ctx, &filer_pb.StatisticsRequest{
+			Collection:  wfs.option.Collection,
+			Replication: wfs.option.Replication,
+			Ttl:         fmt.Sprintf("%ds", wfs.option.TtlSec),
+		})
+		if err != nil {
+			return err
+		}
+		newStats = *resp
+		return nil
+	})
+	if err != nil {
+		return filer_pb.StatisticsResponse{}, err
+	}
+
+	wfs.statsCache = newStats
+	wfs.statsCacheTtl = time.Now().Add(statsCacheDuration)
+
+	return wfs.statsCache, nil
+}
+
+// lookupFileId resolves a chunk's fileId to a fetchable volume server URL via
+// the filer, which proxies the lookup to the master.
+func (wfs *WFS) lookupFileId(fileId string) (targetUrl string, err error) {
+	vid := fileId
+	if commaIdx := strings.Index(fileId, ","); commaIdx > 0 {
+		vid = fileId[:commaIdx]
+	}
+
+	err = wfs.withFilerClient(func(client filer_pb.SeaweedFilerClient) error {
+		resp, lookupErr := client.LookupVolume(context.Background(), &filer_pb.LookupVolumeRequest{
+			VolumeIds: []string{vid},
+		})
+		if lookupErr != nil {
+			return lookupErr
+		}
+		locations, found := resp.LocationsMap[vid]
+		if !found || len(locations.Locations) == 0 {
+			return fmt.Errorf("no location found for volume %s", vid)
+		}
+		targetUrl = fmt.Sprintf("http://%s/%s", locations.Locations[0].Url, fileId)
+		return nil
+	})
+
+	return
+}
+
+// fetchRawChunk returns a chunk's raw bytes (ciphertext when encrypted),
+// consulting the chunk cache before falling back to a volume server fetch.
+// Caching the raw bytes, rather than the decrypted content, keeps the cache
+// cheap to evict and safe at rest. This is also what readahead prefetches
+// call to warm the cache ahead of the foreground reader; ctx cancellation
+// (e.g. the handle being released mid-prefetch) aborts the volume fetch.
+func (wfs *WFS) fetchRawChunk(ctx context.Context, fileId string) (data []byte, err error) {
+
+	if cached, found := wfs.chunkCache.GetChunk(fileId); found {
+		return cached, nil
+	}
+
+	data, err = wfs.volumeFetch(ctx, fileId)
+	if err != nil {
+		return nil, err
+	}
+
+	wfs.chunkCache.SetChunk(fileId, data)
+
+	return data, nil
+}
+
+// volumeFetch fetches a chunk's raw bytes straight from its volume server,
+// bypassing the chunk cache. Its only caller is fetchRawChunk, through which
+// readahead reaches it too; with no chunk cache configured, readahead keeps
+// the result in its own ring buffer instead (see chunkRingBuffer).
+func (wfs *WFS) volumeFetch(ctx context.Context, fileId string) (data []byte, err error) {
+
+	targetUrl, lookupErr := wfs.lookupFileId(fileId)
+	if lookupErr != nil {
+		return nil, lookupErr
+	}
+
+	req, reqErr := http.NewRequestWithContext(ctx, http.MethodGet, targetUrl, nil)
+	if reqErr != nil {
+		return nil, reqErr
+	}
+
+	resp, getErr := http.DefaultClient.Do(req)
+	if getErr != nil {
+		return nil, getErr
+	}
+	defer resp.Body.Close()
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+// readChunkCached returns a chunk's decrypted content; see fetchRawChunk for
+// the caching behavior. Decryption happens on every call, cached or not.
+func (wfs *WFS) readChunkCached(ctx context.Context, fileId string, cipherKey []byte) (data []byte, err error) {
+
+	raw, err := wfs.fetchRawChunk(ctx, fileId)
+	if err != nil {
+		return nil, err
+	}
+
+	return decryptChunk(raw, cipherKey)
+}
+
+// decryptChunk decrypts raw chunk content fetched from a volume server, or
+// returns it unchanged when the chunk isn't encrypted.
+func decryptChunk(raw, cipherKey []byte) ([]byte, error) {
+	if cipherKey == nil {
+		return raw, nil
+	}
+	return util.Decrypt(raw, util.CipherKey(cipherKey))
+}
@@ -0,0 +1,37 @@
+package weed_server
+
+import (
+	"context"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/pb/master_pb"
+)
+
+// LookupVolume relays a volume location lookup to the master, so filer
+// clients (e.g. the FUSE mount) can fetch chunk content directly from a
+// volume server without duplicating the master's topology knowledge.
+func (fs *FilerServer) LookupVolume(ctx context.Context, req *filer_pb.LookupVolumeRequest) (resp *filer_pb.LookupVolumeResponse, err error) {
+
+	output, err := fs.filer.MasterClient.LookupVolume(ctx, &master_pb.LookupVolumeRequest{
+		VolumeIds: req.VolumeIds,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &filer_pb.LookupVolumeResponse{
+		LocationsMap: make(map[string]*filer_pb.Locations),
+	}
+	for vid, locations := range output.LocationsMap {
+		filerLocations := &filer_pb.Locations{}
+		for _, loc := range locations.Locations {
+			filerLocations.Locations = append(filerLocations.Locations, &filer_pb.Location{
+				Url:       loc.Url,
+				PublicUrl: loc.PublicUrl,
+			})
+		}
+		resp.LocationsMap[vid] = filerLocations
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,30 @@
+package weed_server
+
+import (
+	"context"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/filer_pb"
+	"github.com/chrislusf/seaweedfs/weed/pb/master_pb"
+)
+
+// Statistics aggregates cluster-wide disk usage from the master, optionally
+// scoped to a collection, so that filer clients can report real statfs(2) numbers.
+func (fs *FilerServer) Statistics(ctx context.Context, req *filer_pb.StatisticsRequest) (resp *filer_pb.StatisticsResponse, err error) {
+
+	input := &master_pb.StatisticsRequest{
+		Replication: req.Replication,
+		Collection:  req.Collection,
+		Ttl:         req.Ttl,
+	}
+
+	output, err := fs.filer.MasterClient.Statistics(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return &filer_pb.StatisticsResponse{
+		TotalSize: output.TotalSize,
+		UsedSize:  output.UsedSize,
+		FileCount: output.FileCount,
+	}, nil
+}
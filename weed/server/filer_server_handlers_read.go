@@ -1,8 +1,8 @@
 package weed_server
 
 import (
+	"bytes"
 	"context"
-	"fmt"
 	"io"
 	"io/ioutil"
 	"mime"
@@ -143,11 +143,16 @@ func (fs *FilerServer) handleSingleChunk(w http.ResponseWriter, r *http.Request,
 		w.WriteHeader(resp.StatusCode)
 		io.Copy(w, resp.Body)
 	} else {
-		fs.writeEncryptedChunk(w, resp, entry)
+		fs.writeEncryptedChunk(w, r, resp, entry)
 	}
 }
 
-func (fs *FilerServer) writeEncryptedChunk(w http.ResponseWriter, resp *http.Response, entry *filer2.Entry) {
+// writeEncryptedChunk decrypts a single-chunk entry and serves it, honoring
+// any Range header the same way handleMultipleChunks does for plaintext
+// entries. The whole ciphertext still has to be fetched and decrypted up
+// front since the cipher does not support seekable decryption, but the
+// response itself is range-aware, including multipart/byteranges.
+func (fs *FilerServer) writeEncryptedChunk(w http.ResponseWriter, r *http.Request, resp *http.Response, entry *filer2.Entry) {
 	chunk := entry.Chunks[0]
 	encryptedData, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
@@ -161,9 +166,82 @@ func (fs *FilerServer) writeEncryptedChunk(w http.ResponseWriter, resp *http.Res
 		w.WriteHeader(http.StatusNotFound)
 		return
 	}
-	w.Header().Set("Content-Length", fmt.Sprintf("%d", chunk.Size))
-	w.WriteHeader(resp.StatusCode)
-	w.Write(decryptedData)
+
+	totalSize := int64(len(decryptedData))
+	mimeType := entry.Attr.Mime
+
+	rangeReq := r.Header.Get("Range")
+	if rangeReq == "" {
+		w.Header().Set("Content-Length", strconv.FormatInt(totalSize, 10))
+		w.WriteHeader(resp.StatusCode)
+		w.Write(decryptedData)
+		return
+	}
+
+	ranges, err := parseRange(rangeReq, totalSize)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestedRangeNotSatisfiable)
+		return
+	}
+	if sumRangesSize(ranges) > totalSize {
+		// The total number of bytes in all the ranges is larger than the
+		// size of the file itself, so this is probably an attack, or a
+		// dumb client. Ignore the range request.
+		return
+	}
+	if len(ranges) == 0 {
+		return
+	}
+
+	content := bytes.NewReader(decryptedData)
+
+	if len(ranges) == 1 {
+		ra := ranges[0]
+		w.Header().Set("Content-Length", strconv.FormatInt(ra.length, 10))
+		w.Header().Set("Content-Range", ra.contentRange(totalSize))
+		w.WriteHeader(http.StatusPartialContent)
+		content.Seek(ra.start, io.SeekStart)
+		io.CopyN(w, content, ra.length)
+		return
+	}
+
+	// process multiple ranges
+	for _, ra := range ranges {
+		if ra.start > totalSize {
+			http.Error(w, "Out of Range", http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+	}
+	sendSize := rangesMIMESize(ranges, mimeType, totalSize)
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	w.Header().Set("Content-Type", "multipart/byteranges; boundary="+mw.Boundary())
+	sendContent := pr
+	defer pr.Close() // cause writing goroutine to fail and exit if CopyN doesn't finish.
+	go func() {
+		for _, ra := range ranges {
+			part, e := mw.CreatePart(ra.mimeHeader(mimeType, totalSize))
+			if e != nil {
+				pw.CloseWithError(e)
+				return
+			}
+			content.Seek(ra.start, io.SeekStart)
+			if _, e = io.CopyN(part, content, ra.length); e != nil {
+				pw.CloseWithError(e)
+				return
+			}
+		}
+		mw.Close()
+		pw.Close()
+	}()
+	if w.Header().Get("Content-Encoding") == "" {
+		w.Header().Set("Content-Length", strconv.FormatInt(sendSize, 10))
+	}
+	w.WriteHeader(http.StatusPartialContent)
+	if _, err := io.CopyN(w, sendContent, sendSize); err != nil {
+		http.Error(w, "Internal Error", http.StatusInternalServerError)
+		return
+	}
 }
 
 func (fs *FilerServer) handleMultipleChunks(w http.ResponseWriter, r *http.Request, entry *filer2.Entry) {
@@ -0,0 +1,60 @@
+package weed_server
+
+import (
+	"context"
+
+	"github.com/chrislusf/seaweedfs/weed/pb/master_pb"
+	"github.com/chrislusf/seaweedfs/weed/storage"
+	"github.com/chrislusf/seaweedfs/weed/topology"
+)
+
+// Statistics walks the current topology and sums up the disk usage reported
+// by every data node's volumes, optionally scoped by collection, replication
+// type, and/or ttl, so that filers (and through them, FUSE mounts) can answer
+// statfs(2) for real.
+func (ms *MasterServer) Statistics(ctx context.Context, req *master_pb.StatisticsRequest) (*master_pb.StatisticsResponse, error) {
+
+	volumeSizeLimit := uint64(ms.option.VolumeSizeLimitMB) * 1024 * 1024
+
+	// Every replica of a volume is reported by its own data node, so walking
+	// the topology sees the same volume id once per replica. Keep only one
+	// copy per id so totals aren't inflated by the replication factor.
+	uniqueVolumes := make(map[storage.VolumeId]storage.VolumeInfo)
+	for _, vi := range collectVolumeInfos(ms.Topo) {
+		if req.Collection != "" && vi.Collection != req.Collection {
+			continue
+		}
+		if req.Replication != "" && vi.ReplicaPlacement != nil && vi.ReplicaPlacement.String() != req.Replication {
+			continue
+		}
+		if req.Ttl != "" && vi.Ttl != nil && vi.Ttl.String() != req.Ttl {
+			continue
+		}
+		uniqueVolumes[vi.Id] = vi
+	}
+
+	var totalSize, usedSize, fileCount uint64
+	for _, vi := range uniqueVolumes {
+		totalSize += volumeSizeLimit
+		usedSize += vi.Size
+		fileCount += uint64(vi.FileCount)
+	}
+
+	return &master_pb.StatisticsResponse{
+		TotalSize: totalSize,
+		UsedSize:  usedSize,
+		FileCount: fileCount,
+	}, nil
+}
+
+// collectVolumeInfos walks the topology tree (data centers -> racks -> data
+// nodes) and returns every volume reported by a data node's latest heartbeat.
+func collectVolumeInfos(node topology.Node) (volumeInfos []storage.VolumeInfo) {
+	if dn, ok := node.(*topology.DataNode); ok {
+		return dn.GetVolumes()
+	}
+	for _, child := range node.Children() {
+		volumeInfos = append(volumeInfos, collectVolumeInfos(child)...)
+	}
+	return
+}
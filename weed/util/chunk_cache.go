@@ -0,0 +1,257 @@
+package util
+
+import (
+	"container/list"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ChunkCache caches chunk content by fileId. Chunk content is immutable once
+// written, so the fileId alone is a safe, permanent cache key. It is backed by
+// a small in-memory LRU for hot chunks and an optional on-disk LRU (evicted by
+// mtime) for everything that doesn't fit in memory.
+type ChunkCache struct {
+	memCache  *memLruCache
+	diskCache *diskLruCache
+}
+
+// NewChunkCache creates a ChunkCache. memCacheSizeMB<=0 disables the memory
+// tier; cacheDir=="" or diskCacheSizeMB<=0 disables the disk tier.
+func NewChunkCache(memCacheSizeMB int64, cacheDir string, diskCacheSizeMB int64) *ChunkCache {
+	c := &ChunkCache{}
+	if memCacheSizeMB > 0 {
+		c.memCache = newMemLruCache(memCacheSizeMB * 1024 * 1024)
+	}
+	if cacheDir != "" && diskCacheSizeMB > 0 {
+		c.diskCache = newDiskLruCache(cacheDir, diskCacheSizeMB*1024*1024)
+	}
+	return c
+}
+
+// Enabled reports whether either tier is configured. Callers that only make
+// sense with a real cache backing them (e.g. readahead) use this to fall back
+// to a cheaper per-handle substitute instead of prefetching into the void.
+func (c *ChunkCache) Enabled() bool {
+	return c != nil && (c.memCache != nil || c.diskCache != nil)
+}
+
+// GetChunk returns the cached ciphertext/content for fileId, or nil if absent.
+func (c *ChunkCache) GetChunk(fileId string) (data []byte, found bool) {
+	if c == nil {
+		return nil, false
+	}
+	if c.memCache != nil {
+		if data, found = c.memCache.get(fileId); found {
+			return data, true
+		}
+	}
+	if c.diskCache != nil {
+		if data, found = c.diskCache.get(fileId); found {
+			if c.memCache != nil {
+				_, evicted := c.memCache.set(fileId, data)
+				for _, e := range evicted {
+					c.diskCache.set(e.key, e.data)
+				}
+			}
+			return data, true
+		}
+	}
+	return nil, false
+}
+
+// SetChunk stores data for fileId, preferring the memory tier and spilling to
+// the disk tier when the memory tier is disabled or the data is larger than
+// the whole memory tier. Entries the memory
+// tier evicts to make room are also demoted to the disk tier instead of being
+// dropped outright.
+func (c *ChunkCache) SetChunk(fileId string, data []byte) {
+	if c == nil {
+		return
+	}
+	if c.memCache != nil {
+		accepted, evicted := c.memCache.set(fileId, data)
+		if c.diskCache != nil {
+			for _, e := range evicted {
+				c.diskCache.set(e.key, e.data)
+			}
+		}
+		if accepted {
+			return
+		}
+	}
+	if c.diskCache != nil {
+		c.diskCache.set(fileId, data)
+	}
+}
+
+// Stats reports tier sizes for the debug xattr exposed by the mount.
+func (c *ChunkCache) Stats() (memItems int, memBytes int64, diskItems int, diskBytes int64) {
+	if c == nil {
+		return
+	}
+	if c.memCache != nil {
+		memItems, memBytes = c.memCache.stats()
+	}
+	if c.diskCache != nil {
+		diskItems, diskBytes = c.diskCache.stats()
+	}
+	return
+}
+
+// memLruCache is a simple size-bounded, in-memory LRU keyed by fileId.
+type memLruCache struct {
+	sync.Mutex
+	maxSizeBytes int64
+	curSizeBytes int64
+	order        *list.List
+	items        map[string]*list.Element
+}
+
+type memLruEntry struct {
+	key  string
+	data []byte
+}
+
+func newMemLruCache(maxSizeBytes int64) *memLruCache {
+	return &memLruCache{
+		maxSizeBytes: maxSizeBytes,
+		order:        list.New(),
+		items:        make(map[string]*list.Element),
+	}
+}
+
+func (m *memLruCache) get(key string) ([]byte, bool) {
+	m.Lock()
+	defer m.Unlock()
+	if e, found := m.items[key]; found {
+		m.order.MoveToFront(e)
+		return e.Value.(*memLruEntry).data, true
+	}
+	return nil, false
+}
+
+// set stores data if it fits, evicting the oldest entries as needed, and
+// returns those evicted entries so the caller can demote them to the disk
+// tier rather than losing them outright. It returns accepted=false if the
+// data is larger than the entire cache, signalling the caller to fall back
+// to the disk tier instead.
+func (m *memLruCache) set(key string, data []byte) (accepted bool, evicted []memLruEntry) {
+	size := int64(len(data))
+	if size > m.maxSizeBytes {
+		return false, nil
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	if e, found := m.items[key]; found {
+		m.curSizeBytes -= int64(len(e.Value.(*memLruEntry).data))
+		m.order.Remove(e)
+		delete(m.items, key)
+	}
+
+	for m.curSizeBytes+size > m.maxSizeBytes && m.order.Len() > 0 {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		entry := oldest.Value.(*memLruEntry)
+		delete(m.items, entry.key)
+		m.curSizeBytes -= int64(len(entry.data))
+		evicted = append(evicted, *entry)
+	}
+
+	e := m.order.PushFront(&memLruEntry{key: key, data: data})
+	m.items[key] = e
+	m.curSizeBytes += size
+
+	return true, evicted
+}
+
+func (m *memLruCache) stats() (items int, bytes int64) {
+	m.Lock()
+	defer m.Unlock()
+	return len(m.items), m.curSizeBytes
+}
+
+// diskLruCache stores each chunk as a file under dir, named by fileId, and
+// evicts the least-recently-used files (by mtime) once maxSizeBytes is exceeded.
+type diskLruCache struct {
+	sync.Mutex
+	dir          string
+	maxSizeBytes int64
+}
+
+func newDiskLruCache(dir string, maxSizeBytes int64) *diskLruCache {
+	os.MkdirAll(dir, 0755)
+	return &diskLruCache{dir: dir, maxSizeBytes: maxSizeBytes}
+}
+
+func (d *diskLruCache) pathFor(key string) string {
+	return filepath.Join(d.dir, url.QueryEscape(key))
+}
+
+func (d *diskLruCache) get(key string) ([]byte, bool) {
+	data, err := ioutil.ReadFile(d.pathFor(key))
+	if err != nil {
+		return nil, false
+	}
+	now := time.Now()
+	os.Chtimes(d.pathFor(key), now, now)
+	return data, true
+}
+
+func (d *diskLruCache) set(key string, data []byte) {
+	d.Lock()
+	defer d.Unlock()
+
+	if err := ioutil.WriteFile(d.pathFor(key), data, 0644); err != nil {
+		return
+	}
+	d.evictIfNeeded()
+}
+
+func (d *diskLruCache) evictIfNeeded() {
+	entries, err := ioutil.ReadDir(d.dir)
+	if err != nil {
+		return
+	}
+
+	var total int64
+	for _, fi := range entries {
+		total += fi.Size()
+	}
+
+	if total <= d.maxSizeBytes {
+		return
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ModTime().Before(entries[j].ModTime())
+	})
+
+	for _, fi := range entries {
+		if total <= d.maxSizeBytes {
+			break
+		}
+		full := filepath.Join(d.dir, fi.Name())
+		if err := os.Remove(full); err == nil {
+			total -= fi.Size()
+		}
+	}
+}
+
+func (d *diskLruCache) stats() (items int, bytes int64) {
+	entries, err := ioutil.ReadDir(d.dir)
+	if err != nil {
+		return 0, 0
+	}
+	for _, fi := range entries {
+		items++
+		bytes += fi.Size()
+	}
+	return
+}
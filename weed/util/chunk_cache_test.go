@@ -0,0 +1,130 @@
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestMemLruCacheEvictsOldest(t *testing.T) {
+	m := newMemLruCache(10) // bytes
+
+	m.set("a", []byte("12345")) // 5 bytes
+	m.set("b", []byte("12345")) // 5 bytes, cache now full
+
+	if _, found := m.get("a"); !found {
+		t.Fatalf("expected \"a\" to still be cached before eviction")
+	}
+
+	// touching "a" makes "b" the least recently used entry
+	m.set("c", []byte("12345")) // forces eviction of "b"
+
+	if _, found := m.get("b"); found {
+		t.Fatalf("expected \"b\" to be evicted as the least recently used entry")
+	}
+	if _, found := m.get("a"); !found {
+		t.Fatalf("expected \"a\" to survive eviction since it was touched more recently")
+	}
+	if _, found := m.get("c"); !found {
+		t.Fatalf("expected \"c\" to be cached")
+	}
+}
+
+func TestMemLruCacheRejectsOversizedEntry(t *testing.T) {
+	m := newMemLruCache(4)
+
+	if accepted, _ := m.set("too-big", []byte("12345")); accepted {
+		t.Fatalf("expected set to reject an entry larger than the whole cache")
+	}
+	if _, found := m.get("too-big"); found {
+		t.Fatalf("oversized entry should not be cached")
+	}
+}
+
+func TestMemLruCacheReturnsEvictedEntries(t *testing.T) {
+	m := newMemLruCache(10) // bytes
+
+	m.set("a", []byte("12345")) // 5 bytes
+	m.set("b", []byte("12345")) // 5 bytes, cache now full
+
+	accepted, evicted := m.set("c", []byte("12345")) // forces eviction of "a"
+	if !accepted {
+		t.Fatalf("expected \"c\" to be accepted")
+	}
+	if len(evicted) != 1 || evicted[0].key != "a" || string(evicted[0].data) != "12345" {
+		t.Fatalf("expected set to return the evicted entry for \"a\", got %+v", evicted)
+	}
+}
+
+func TestDiskLruCacheEvictsByMtime(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chunkcache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	d := newDiskLruCache(dir, 10) // bytes
+
+	d.set("old", []byte("12345")) // 5 bytes
+	time.Sleep(10 * time.Millisecond)
+	d.set("new", []byte("12345")) // cache now at capacity (10 bytes)
+	time.Sleep(10 * time.Millisecond)
+
+	d.set("newest", []byte("12345")) // forces eviction of the least recently written entry
+
+	if _, found := d.get("old"); found {
+		t.Fatalf("expected \"old\" to be evicted as the least recently used entry")
+	}
+	if _, found := d.get("new"); !found {
+		t.Fatalf("expected \"new\" to survive eviction")
+	}
+	if _, found := d.get("newest"); !found {
+		t.Fatalf("expected \"newest\" to be cached")
+	}
+}
+
+func TestChunkCacheMemThenDiskTier(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chunkcache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := NewChunkCache(1, dir, 10) // 1MB mem tier, 10MB disk tier
+
+	c.SetChunk("a", []byte("hello"))
+
+	data, found := c.GetChunk("a")
+	if !found || string(data) != "hello" {
+		t.Fatalf("expected chunk \"a\" to be retrievable after SetChunk")
+	}
+}
+
+// TestChunkCacheEvictionDemotesToDisk forces the memory tier to evict "a" by
+// filling it past capacity, then asserts "a" is still retrievable from the
+// disk tier rather than lost outright.
+func TestChunkCacheEvictionDemotesToDisk(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chunkcache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := &ChunkCache{
+		memCache:  newMemLruCache(10), // bytes, just enough for one 10-byte chunk
+		diskCache: newDiskLruCache(dir, 1024),
+	}
+
+	c.SetChunk("a", []byte("1234567890")) // fills the mem tier
+	c.SetChunk("b", []byte("1234567890")) // evicts "a" from the mem tier
+
+	if _, found := c.memCache.get("a"); found {
+		t.Fatalf("expected \"a\" to have been evicted from the mem tier")
+	}
+
+	data, found := c.GetChunk("a")
+	if !found || string(data) != "1234567890" {
+		t.Fatalf("expected \"a\" to have been demoted to the disk tier and still retrievable, found=%v", found)
+	}
+}